@@ -0,0 +1,272 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kobject holds the intermediate representation that every loader
+// populates and every transformer consumes. Loaders translate a source
+// format (Compose, Bundle, ...) into a KomposeObject; transformers turn a
+// KomposeObject into the Kubernetes/OpenShift objects that get written out.
+package kobject
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// KomposeObject holds the generic struct of Kompose transformation
+type KomposeObject struct {
+	ServiceConfigs map[string]ServiceConfig
+
+	// NamedVolumes holds the top-level `volumes:` section of the source
+	// file, keyed by volume name.
+	NamedVolumes map[string]NamedVolumeConfig
+
+	// ConfigMaps holds the top-level `configs:` section of the source
+	// file, keyed by config name.
+	ConfigMaps map[string]FileConfig
+
+	// Secrets holds the top-level `secrets:` section of the source file,
+	// keyed by secret name.
+	Secrets map[string]FileConfig
+}
+
+// ServiceConfig holds the basic struct of a container
+type ServiceConfig struct {
+	ContainerName string
+	Image         string
+	Environment   []EnvVar
+	Port          []Ports
+	Command       []string
+	WorkingDir    string
+	Args          []string
+	Volumes       []string
+	Labels        map[string]string
+	Annotations   map[string]string
+	CPUSet        string
+	CPUShares     int64
+	CPUQuota      int64
+	CapAdd        []string
+	CapDrop       []string
+	Expose        []string
+	Privileged    bool
+	Restart       string
+	User          string
+	VolumesFrom   []string
+	ServiceType   string
+
+	// Networks is the list of compose v3 networks this service attaches
+	// to; their order is not defined.
+	Networks []string
+
+	// NamedVolumes is the subset of Volumes that reference a top-level
+	// named volume (as opposed to a host bind mount or anonymous
+	// volume), already split into source/target/options.
+	NamedVolumes []ServiceVolumeConfig
+
+	// Configs lists the top-level configs this service mounts, via the
+	// service-level `configs:` entries.
+	Configs []FileReference
+
+	// Secrets lists the top-level secrets this service mounts, via the
+	// service-level `secrets:` entries.
+	Secrets []FileReference
+
+	// HealthCheck is the compose v3 `healthcheck:` block, carried over
+	// field by field; nil when the service has none.
+	HealthCheck *HealthCheckConfig
+
+	// Replicas is the desired replica count taken from
+	// `deploy.replicas`. Nil means "unset", letting the transformer fall
+	// back to its own default.
+	Replicas *int32
+
+	// Resources holds the compute resource requests/limits translated
+	// from `deploy.resources`.
+	Resources ResourceConfig
+
+	// Profiles lists the compose v3 `profiles:` this service belongs to.
+	// An empty list means the service is always active.
+	Profiles []string
+
+	// DependsOn records `depends_on` conditions (service_started,
+	// service_healthy, service_completed_successfully), keyed by the
+	// dependency's service name.
+	DependsOn map[string]string
+
+	// ImagePullSecret is the name of the Secret to reference from
+	// imagePullSecrets, set via the "kompose.image-pull-secret" label.
+	ImagePullSecret string
+
+	// ImagePullPolicy sets the container's imagePullPolicy, via the
+	// "kompose.image-pull-policy" label.
+	ImagePullPolicy string
+
+	// ServiceAccountName sets the pod spec's serviceAccountName, via the
+	// "kompose.service-account-name" label.
+	ServiceAccountName string
+
+	// NodeSelector sets the pod spec's nodeSelector, via the
+	// "kompose.node-selector.<key>" label family.
+	NodeSelector map[string]string
+
+	// ExposeService holds the value of the "kompose.expose" label,
+	// distinct from the compose `expose:` keyword: it controls whether
+	// (and under what host) kompose should generate an Ingress.
+	ExposeService string
+
+	// ControllerType selects the workload kind kompose should generate
+	// for this service (Deployment, StatefulSet, DaemonSet or Job), via
+	// the "kompose.controller.type" label.
+	ControllerType string
+
+	// UpdateConfig is `deploy.update_config`, translated into the
+	// workload's `strategy.rollingUpdate`.
+	UpdateConfig *UpdateConfig
+
+	// RestartPolicy is `deploy.restart_policy`, translated into the pod
+	// spec's `restartPolicy`.
+	RestartPolicy *RestartPolicy
+
+	// PlacementConstraints is `deploy.placement.constraints`. Only
+	// "node.labels.<key>==<value>" / "engine.labels.<key>==<value>"
+	// entries are translated, into the pod spec's `nodeSelector`; other
+	// forms are dropped and no `nodeAffinity` is generated.
+	PlacementConstraints []string
+
+	// DeployLabels is `deploy.labels`, applied to the workload's own
+	// metadata rather than the pod template's, unlike the service-level
+	// `labels:` that become Annotations above.
+	DeployLabels map[string]string
+
+	// EndpointMode is `deploy.endpoint_mode`; "dnsrr" maps to a
+	// headless Service (`clusterIP: None`).
+	EndpointMode string
+
+	// HPA holds the `kompose.hpa.*` label family, non-nil only when at
+	// least one of them is set.
+	HPA *HorizontalPodAutoscaler
+}
+
+// UpdateConfig mirrors `deploy.update_config`.
+type UpdateConfig struct {
+	Parallelism *uint64
+	// Order is "stop-first" or "start-first"; the transformer maps
+	// "stop-first" onto a Recreate deployment strategy.
+	Order string
+}
+
+// RestartPolicy mirrors `deploy.restart_policy`.
+type RestartPolicy struct {
+	Condition   string
+	MaxAttempts *uint64
+}
+
+// HorizontalPodAutoscaler holds the `kompose.hpa.min` / `kompose.hpa.max` /
+// `kompose.hpa.cpu-target` label family, used to generate an
+// autoscaling HorizontalPodAutoscaler (CPU utilization target only)
+// alongside the workload.
+type HorizontalPodAutoscaler struct {
+	Min       string
+	Max       string
+	CPUTarget string
+}
+
+// EnvVar holds the environment variable struct of a container
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// Ports holds the ports struct of a container
+type Ports struct {
+	HostPort      int32
+	ContainerPort int32
+	Protocol      api.Protocol
+
+	// HostIP is the host-side bind address from the long form's
+	// `host_ip:` (or the short form's "127.0.0.1:8001:8001" prefix).
+	HostIP string
+
+	// Name is the long form's `name:`, used as the Service port's name
+	// whenever it is set.
+	Name string
+
+	// AppProtocol is the long form's `app_protocol:`. Nil when unset. The
+	// Kubernetes transformer does not copy it onto the generated
+	// Service's ports.
+	AppProtocol *string
+}
+
+// NamedVolumeConfig holds the top-level `volumes:` entry for a single named
+// volume, as it should be translated into a PersistentVolumeClaim.
+type NamedVolumeConfig struct {
+	Driver     string
+	DriverOpts map[string]string
+	External   bool
+	// ExternalName is the name of the pre-existing PVC to reference when
+	// External is true, if different from the volume's own name.
+	ExternalName string
+}
+
+// ServiceVolumeConfig is a single service-level volume entry that resolves
+// to a top-level named volume.
+type ServiceVolumeConfig struct {
+	VolumeName string
+	Target     string
+	ReadOnly   bool
+}
+
+// FileConfig holds a top-level `configs:`/`secrets:` entry.
+type FileConfig struct {
+	// File is the `file:` path, already resolved against the compose
+	// file's directory, that the contents were (or should be) read from.
+	File string
+	// Content holds the file's contents, read at load time.
+	Content []byte
+	External bool
+}
+
+// FileReference is a service-level reference to a top-level config or
+// secret, via either the short form (name only) or the long form
+// (source/target/mode).
+type FileReference struct {
+	Source string
+	Target string
+	Mode   *uint32
+}
+
+// HealthCheckConfig mirrors the compose v3 `healthcheck:` block.
+type HealthCheckConfig struct {
+	Test        []string
+	Interval    string
+	Timeout     string
+	Retries     *uint64
+	StartPeriod string
+	Disable     bool
+}
+
+// ResourceConfig holds the compute resources translated from
+// `deploy.resources`.
+type ResourceConfig struct {
+	Limits   Resource
+	Requests Resource
+}
+
+// Resource holds a single limits/requests entry. Compose's
+// `deploy.resources` only defines `cpus` and `memory` (no
+// `ephemeral_storage`), so that's all this carries.
+type Resource struct {
+	CPU    string
+	Memory string
+}
@@ -17,237 +17,315 @@ limitations under the License.
 package compose
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
 	"k8s.io/kubernetes/pkg/api"
 
-	"github.com/Sirupsen/logrus"
-	"github.com/docker/libcompose/config"
-	"github.com/docker/libcompose/lookup"
-	"github.com/docker/libcompose/project"
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+	"github.com/kubernetes-incubator/kompose/pkg/loader/compose/ports"
+	"github.com/kubernetes-incubator/kompose/pkg/schema"
 )
 
 type Compose struct {
 }
 
 // load environment variables from compose file
-func loadEnvVars(envars []string) []kobject.EnvVar {
+func loadEnvVars(envars types.MappingWithEquals) []kobject.EnvVar {
 	envs := []kobject.EnvVar{}
-	for _, e := range envars {
-		character := ""
-		equalPos := strings.Index(e, "=")
-		colonPos := strings.Index(e, ":")
-		switch {
-		case equalPos == -1 && colonPos == -1:
-			character = ""
-		case equalPos == -1 && colonPos != -1:
-			character = ":"
-		case equalPos != -1 && colonPos == -1:
-			character = "="
-		case equalPos != -1 && colonPos != -1:
-			if equalPos > colonPos {
-				character = ":"
-			} else {
-				character = "="
-			}
-		}
-
-		if character == "" {
-			envs = append(envs, kobject.EnvVar{
-				Name: e,
-			})
-		} else {
-			values := strings.SplitN(e, character, 2)
-			envs = append(envs, kobject.EnvVar{
-				Name:  values[0],
-				Value: values[1],
-			})
+	for name, value := range envars {
+		if value == nil {
+			envs = append(envs, kobject.EnvVar{Name: name})
+			continue
 		}
+		envs = append(envs, kobject.EnvVar{Name: name, Value: *value})
 	}
-
 	return envs
 }
 
-// Load ports from compose file
-func loadPorts(composePorts []string) ([]kobject.Ports, error) {
-	ports := []kobject.Ports{}
-	character := ":"
-	for _, port := range composePorts {
-		proto := api.ProtocolTCP
-		// get protocol
-		p := strings.Split(port, "/")
-		if len(p) == 2 {
-			if strings.EqualFold("tcp", p[1]) {
-				proto = api.ProtocolTCP
-			} else if strings.EqualFold("udp", p[1]) {
-				proto = api.ProtocolUDP
-			}
-		}
-		// port mappings without protocol part
-		portNoProto := p[0]
-		if strings.Contains(portNoProto, character) {
-			hostPort := portNoProto[0:strings.Index(portNoProto, character)]
-			hostPort = strings.TrimSpace(hostPort)
-			hostPortInt, err := strconv.Atoi(hostPort)
-			if err != nil {
-				return nil, fmt.Errorf("invalid host port %q", port)
-			}
-			containerPort := portNoProto[strings.Index(portNoProto, character)+1:]
-			containerPort = strings.TrimSpace(containerPort)
-			containerPortInt, err := strconv.Atoi(containerPort)
-			if err != nil {
-				return nil, fmt.Errorf("invalid container port %q", port)
-			}
-			ports = append(ports, kobject.Ports{
-				HostPort:      int32(hostPortInt),
-				ContainerPort: int32(containerPortInt),
-				Protocol:      proto,
-			})
-		} else {
-			containerPortInt, err := strconv.Atoi(portNoProto)
-			if err != nil {
-				return nil, fmt.Errorf("invalid container port %q", port)
-			}
-			ports = append(ports, kobject.Ports{
-				ContainerPort: int32(containerPortInt),
-				Protocol:      proto,
-			})
-		}
-
-	}
-	return ports, nil
-}
-
-// load compose file into KomposeObject
-func (c *Compose) LoadFile(file string) kobject.KomposeObject {
+// LoadFile loads one or more compose files into a single KomposeObject. When
+// more than one file is given they are merged in order via compose-go, so a
+// later file's services/networks/volumes override or extend an earlier
+// one's, matching `docker compose -f a.yml -f b.yml`. extends:, include: and
+// ${VAR:-default} interpolation are all resolved by the library itself.
+func (c *Compose) LoadFile(files []string) (kobject.KomposeObject, error) {
 	komposeObject := kobject.KomposeObject{
 		ServiceConfigs: make(map[string]kobject.ServiceConfig),
+		NamedVolumes:   make(map[string]kobject.NamedVolumeConfig),
+		ConfigMaps:     make(map[string]kobject.FileConfig),
+		Secrets:        make(map[string]kobject.FileConfig),
 	}
-	context := &project.Context{}
-	if file == "" {
-		file = "docker-compose.yml"
-	}
-	context.ComposeFiles = []string{file}
 
-	if context.ResourceLookup == nil {
-		context.ResourceLookup = &lookup.FileResourceLookup{}
+	if len(files) == 0 {
+		files = []string{"docker-compose.yml"}
 	}
 
-	if context.EnvironmentLookup == nil {
-		cwd, err := os.Getwd()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
 		if err != nil {
-			return kobject.KomposeObject{}
+			return kobject.KomposeObject{}, fmt.Errorf("failed to read %s: %v", file, err)
 		}
-		context.EnvironmentLookup = &lookup.ComposableEnvLookup{
-			Lookups: []config.EnvironmentLookup{
-				&lookup.EnvfileLookup{
-					Path: filepath.Join(cwd, ".env"),
-				},
-				&lookup.OsEnvLookup{},
-			},
+		violations, err := schema.Validate(file, data)
+		if err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("failed to validate %s: %v", file, err)
+		}
+		if len(violations) > 0 {
+			msgs := make([]string, len(violations))
+			for i, v := range violations {
+				msgs[i] = v.Error()
+			}
+			return kobject.KomposeObject{}, fmt.Errorf("%s is not a valid compose file:\n%s", file, strings.Join(msgs, "\n"))
 		}
 	}
 
-	// load compose file into composeObject
-	composeObject := project.NewProject(context, nil, nil)
-	err := composeObject.Parse()
+	options, err := cli.NewProjectOptions(files,
+		cli.WithOsEnv,
+		cli.WithDotEnv,
+		cli.WithResolvedPaths(true),
+	)
 	if err != nil {
-		logrus.Fatalf("Failed to load compose file: %v", err)
+		return kobject.KomposeObject{}, fmt.Errorf("failed to configure compose loader: %v", err)
 	}
 
-	// transform composeObject into komposeObject
-	composeServiceNames := composeObject.ServiceConfigs.Keys()
+	project, err := options.LoadProject(context.Background())
+	if err != nil {
+		return kobject.KomposeObject{}, fmt.Errorf("failed to load compose file(s) %v: %v", files, err)
+	}
 
-	// volume config and network config are not supported
-	if len(composeObject.NetworkConfigs) > 0 {
-		logrus.Warningf("Unsupported network configuration of compose v2 - ignoring")
+	for name, volume := range project.Volumes {
+		komposeObject.NamedVolumes[name] = kobject.NamedVolumeConfig{
+			Driver:       volume.Driver,
+			DriverOpts:   volume.DriverOpts,
+			External:     volume.External.Bool(),
+			ExternalName: volume.Name,
+		}
 	}
-	if len(composeObject.VolumeConfigs) > 0 {
-		logrus.Warningf("Unsupported volume configuration of compose v2 - ignoring")
+
+	for name, config := range project.Configs {
+		fc, err := loadFileConfig(config.File, config.Content, config.External.Bool())
+		if err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("config %q: %v", name, err)
+		}
+		komposeObject.ConfigMaps[name] = fc
 	}
 
-	networksWarningFound := false
+	for name, secret := range project.Secrets {
+		fc, err := loadFileConfig(secret.File, secret.Content, secret.External.Bool())
+		if err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("secret %q: %v", name, err)
+		}
+		komposeObject.Secrets[name] = fc
+	}
 
-	for _, name := range composeServiceNames {
-		if composeServiceConfig, ok := composeObject.ServiceConfigs.Get(name); ok {
-			//FIXME: networks always contains one default element, even it isn't declared in compose v2.
-			if composeServiceConfig.Networks != nil && len(composeServiceConfig.Networks.Networks) > 0 &&
-				composeServiceConfig.Networks.Networks[0].Name != "default" &&
-				!networksWarningFound {
-				logrus.Warningf("Unsupported key networks - ignoring")
-				networksWarningFound = true
-			}
-			kobject.CheckUnsupportedKey(composeServiceConfig)
-			serviceConfig := kobject.ServiceConfig{}
-			serviceConfig.Image = composeServiceConfig.Image
-			serviceConfig.ContainerName = composeServiceConfig.ContainerName
-			serviceConfig.Command = composeServiceConfig.Entrypoint
-			serviceConfig.Args = composeServiceConfig.Command
-
-			envs := loadEnvVars(composeServiceConfig.Environment)
-			serviceConfig.Environment = envs
-
-			// load ports
-			ports, err := loadPorts(composeServiceConfig.Ports)
-			if err != nil {
-				logrus.Fatalf("%q failed to load ports from compose file: %v", name, err)
-			}
-			serviceConfig.Port = ports
+	for name, composeServiceConfig := range project.Services {
+		serviceConfig := kobject.ServiceConfig{}
+		serviceConfig.Image = composeServiceConfig.Image
+		serviceConfig.ContainerName = composeServiceConfig.ContainerName
+		serviceConfig.Command = composeServiceConfig.Entrypoint
+		serviceConfig.Args = composeServiceConfig.Command
 
-			serviceConfig.WorkingDir = composeServiceConfig.WorkingDir
+		serviceConfig.Environment = loadEnvVars(composeServiceConfig.Environment)
 
-			if composeServiceConfig.Volumes != nil {
-				for _, volume := range composeServiceConfig.Volumes.Volumes {
-					serviceConfig.Volumes = append(serviceConfig.Volumes, volume.String())
-				}
-			}
+		servicePorts, err := ports.Parse(composeServiceConfig.Ports)
+		if err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("%q failed to load ports from compose file: %v", name, err)
+		}
+		serviceConfig.Port = servicePorts
 
-			// canonical "Custom Labels" handler
-			// Labels used to influence conversion of kompose will be handled
-			// from here for docker-compose. Each loader will have such handler.
-			for key, value := range composeServiceConfig.Labels {
-				switch key {
-				case "kompose.service.type":
-					serviceConfig.ServiceType = handleServiceType(value)
+		serviceConfig.WorkingDir = composeServiceConfig.WorkingDir
+
+		for _, volume := range composeServiceConfig.Volumes {
+			if volume.Type == types.VolumeTypeVolume && volume.Source != "" {
+				if _, ok := komposeObject.NamedVolumes[volume.Source]; ok {
+					serviceConfig.NamedVolumes = append(serviceConfig.NamedVolumes, kobject.ServiceVolumeConfig{
+						VolumeName: volume.Source,
+						Target:     volume.Target,
+						ReadOnly:   volume.ReadOnly,
+					})
+					continue
 				}
 			}
+			serviceConfig.Volumes = append(serviceConfig.Volumes, volume.String())
+		}
 
-			// convert compose labels to annotations
-			serviceConfig.Annotations = map[string]string(composeServiceConfig.Labels)
+		for name := range composeServiceConfig.Networks {
+			serviceConfig.Networks = append(serviceConfig.Networks, name)
+		}
 
-			serviceConfig.CPUSet = composeServiceConfig.CPUSet
-			serviceConfig.CPUShares = int64(composeServiceConfig.CPUShares)
-			serviceConfig.CPUQuota = int64(composeServiceConfig.CPUQuota)
-			serviceConfig.CapAdd = composeServiceConfig.CapAdd
-			serviceConfig.CapDrop = composeServiceConfig.CapDrop
-			serviceConfig.Expose = composeServiceConfig.Expose
-			serviceConfig.Privileged = composeServiceConfig.Privileged
+		// canonical "Custom Labels" handler
+		// Labels used to influence conversion of kompose will be handled
+		// from here for docker-compose. Each loader will have such handler.
+		if err := applyKomposeLabels(&serviceConfig, composeServiceConfig.Labels); err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("%q: %v", name, err)
+		}
+		if err := applyKomposeExtensions(&serviceConfig, composeServiceConfig.Extensions); err != nil {
+			return kobject.KomposeObject{}, fmt.Errorf("%q: %v", name, err)
+		}
+
+		// convert compose labels to annotations
+		serviceConfig.Annotations = map[string]string(composeServiceConfig.Labels)
+
+		serviceConfig.CPUSet = composeServiceConfig.CPUSet
+		serviceConfig.CPUShares = composeServiceConfig.CPUShares
+		serviceConfig.CPUQuota = composeServiceConfig.CPUQuota
+		serviceConfig.CapAdd = composeServiceConfig.CapAdd
+		serviceConfig.CapDrop = composeServiceConfig.CapDrop
+		serviceConfig.Expose = composeServiceConfig.Expose
+		serviceConfig.Privileged = composeServiceConfig.Privileged
+		if composeServiceConfig.Restart != "" {
 			serviceConfig.Restart = composeServiceConfig.Restart
-			serviceConfig.User = composeServiceConfig.User
-			serviceConfig.VolumesFrom = composeServiceConfig.VolumesFrom
+		}
+		serviceConfig.User = composeServiceConfig.User
+		serviceConfig.VolumesFrom = composeServiceConfig.VolumesFrom
 
-			komposeObject.ServiceConfigs[name] = serviceConfig
+		if composeServiceConfig.HealthCheck != nil {
+			serviceConfig.HealthCheck = loadHealthCheck(composeServiceConfig.HealthCheck)
+		}
+
+		for _, ref := range composeServiceConfig.Configs {
+			serviceConfig.Configs = append(serviceConfig.Configs, kobject.FileReference{
+				Source: ref.Source,
+				Target: ref.Target,
+				Mode:   ref.Mode,
+			})
+		}
+
+		for _, ref := range composeServiceConfig.Secrets {
+			serviceConfig.Secrets = append(serviceConfig.Secrets, kobject.FileReference{
+				Source: ref.Source,
+				Target: ref.Target,
+				Mode:   ref.Mode,
+			})
+		}
+
+		loadDeploy(&serviceConfig, composeServiceConfig.Deploy)
+
+		serviceConfig.Profiles = composeServiceConfig.Profiles
+
+		if len(composeServiceConfig.DependsOn) > 0 {
+			serviceConfig.DependsOn = make(map[string]string, len(composeServiceConfig.DependsOn))
+			for depName, dep := range composeServiceConfig.DependsOn {
+				serviceConfig.DependsOn[depName] = dep.Condition
+			}
+		}
+
+		komposeObject.ServiceConfigs[name] = serviceConfig
+	}
+
+	return komposeObject, nil
+}
+
+// loadFileConfig builds a kobject.FileConfig for a top-level `configs:` or
+// `secrets:` entry. External entries reference an object that's expected
+// to already exist in the cluster, so their contents are never read.
+// Otherwise, if compose-go hasn't already inlined the content (e.g. from
+// a `content:` key), it's read from `file:` relative to the compose file.
+func loadFileConfig(file string, content []byte, external bool) (kobject.FileConfig, error) {
+	fc := kobject.FileConfig{File: file, External: external}
+	if external {
+		return fc, nil
+	}
+	if len(content) > 0 {
+		fc.Content = content
+		return fc, nil
+	}
+	if file == "" {
+		return fc, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return kobject.FileConfig{}, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	fc.Content = data
+	return fc, nil
+}
+
+// loadDeploy translates a compose v3 `deploy:` block onto serviceConfig.
+// It's a no-op when the service has no `deploy:` section at all.
+func loadDeploy(serviceConfig *kobject.ServiceConfig, deploy *types.DeployConfig) {
+	if deploy == nil {
+		return
+	}
+
+	if deploy.Replicas != nil {
+		replicas := int32(*deploy.Replicas)
+		serviceConfig.Replicas = &replicas
+	}
+
+	// MemoryBytes is a plain byte count; format it as a bare integer
+	// rather than via its docker-style String() ("128MB"), which
+	// Kubernetes' resource.Quantity grammar ("128M"/"128Mi") doesn't
+	// accept.
+	if limits := deploy.Resources.Limits; limits != nil {
+		serviceConfig.Resources.Limits = kobject.Resource{
+			CPU:    limits.NanoCPUs,
+			Memory: strconv.FormatInt(int64(limits.MemoryBytes), 10),
+		}
+	}
+	if reservations := deploy.Resources.Reservations; reservations != nil {
+		serviceConfig.Resources.Requests = kobject.Resource{
+			CPU:    reservations.NanoCPUs,
+			Memory: strconv.FormatInt(int64(reservations.MemoryBytes), 10),
+		}
+	}
+
+	if uc := deploy.UpdateConfig; uc != nil {
+		serviceConfig.UpdateConfig = &kobject.UpdateConfig{
+			Parallelism: uc.Parallelism,
+			Order:       uc.Order,
 		}
 	}
 
-	return komposeObject
+	if rp := deploy.RestartPolicy; rp != nil {
+		serviceConfig.RestartPolicy = &kobject.RestartPolicy{
+			Condition:   rp.Condition,
+			MaxAttempts: rp.MaxAttempts,
+		}
+	}
+
+	serviceConfig.PlacementConstraints = deploy.Placement.Constraints
+
+	if len(deploy.Labels) > 0 {
+		serviceConfig.DeployLabels = map[string]string(deploy.Labels)
+	}
+
+	serviceConfig.EndpointMode = deploy.EndpointMode
+}
+
+// loadHealthCheck translates a compose v3 `healthcheck:` block into its
+// kobject representation.
+func loadHealthCheck(h *types.HealthCheckConfig) *kobject.HealthCheckConfig {
+	hc := &kobject.HealthCheckConfig{
+		Test:    h.Test,
+		Disable: h.Disable,
+	}
+	if h.Interval != nil {
+		hc.Interval = h.Interval.String()
+	}
+	if h.Timeout != nil {
+		hc.Timeout = h.Timeout.String()
+	}
+	if h.StartPeriod != nil {
+		hc.StartPeriod = h.StartPeriod.String()
+	}
+	hc.Retries = h.Retries
+	return hc
 }
 
-func handleServiceType(ServiceType string) string {
+func handleServiceType(ServiceType string) (string, error) {
 	switch strings.ToLower(ServiceType) {
 	case "", "clusterip":
-		return string(api.ServiceTypeClusterIP)
+		return string(api.ServiceTypeClusterIP), nil
 	case "nodeport":
-		return string(api.ServiceTypeNodePort)
+		return string(api.ServiceTypeNodePort), nil
 	case "loadbalancer":
-		return string(api.ServiceTypeLoadBalancer)
+		return string(api.ServiceTypeLoadBalancer), nil
 	default:
-		logrus.Fatalf("Unknown value '%s', supported values are 'NodePort, ClusterIP or LoadBalancer'", ServiceType)
-		return ""
+		return "", fmt.Errorf("unknown value '%s', supported values are 'NodePort, ClusterIP or LoadBalancer'", ServiceType)
 	}
 }
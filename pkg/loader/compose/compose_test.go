@@ -0,0 +1,157 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileConfigExternal(t *testing.T) {
+	fc, err := loadFileConfig("", nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fc.External || len(fc.Content) != 0 {
+		t.Errorf("loadFileConfig(external) = %+v, want External with no content read", fc)
+	}
+}
+
+func TestLoadFileConfigInlineContent(t *testing.T) {
+	fc, err := loadFileConfig("ignored.txt", []byte("inline content"), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(fc.Content) != "inline content" {
+		t.Errorf("Content = %q, want %q", fc.Content, "inline content")
+	}
+}
+
+func TestLoadFileConfigReadsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.txt")
+	if err := os.WriteFile(path, []byte("from disk"), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	fc, err := loadFileConfig(path, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(fc.Content) != "from disk" {
+		t.Errorf("Content = %q, want %q", fc.Content, "from disk")
+	}
+	if fc.File != path {
+		t.Errorf("File = %q, want %q", fc.File, path)
+	}
+}
+
+func TestLoadFileConfigMissingFile(t *testing.T) {
+	if _, err := loadFileConfig(filepath.Join(t.TempDir(), "missing.txt"), nil, false); err == nil {
+		t.Fatal("expected an error for a config file that doesn't exist")
+	}
+}
+
+func writeFixture(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write fixture %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadFileSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "docker-compose.yml", `
+services:
+  web:
+    image: nginx
+    ports:
+      - "8080:80"
+    environment:
+      FOO: bar
+`)
+
+	komposeObject, err := (&Compose{}).LoadFile([]string{file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	web, ok := komposeObject.ServiceConfigs["web"]
+	if !ok {
+		t.Fatal("expected a \"web\" service")
+	}
+	if web.Image != "nginx" {
+		t.Errorf("Image = %q, want %q", web.Image, "nginx")
+	}
+	if len(web.Port) != 1 || web.Port[0].HostPort != 8080 || web.Port[0].ContainerPort != 80 {
+		t.Errorf("Port = %+v", web.Port)
+	}
+	if len(web.Environment) != 1 || web.Environment[0].Name != "FOO" || web.Environment[0].Value != "bar" {
+		t.Errorf("Environment = %+v", web.Environment)
+	}
+}
+
+func TestLoadFileMultiFileOverride(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFixture(t, dir, "docker-compose.yml", `
+services:
+  web:
+    image: nginx
+    environment:
+      FOO: base
+`)
+	override := writeFixture(t, dir, "docker-compose.override.yml", `
+services:
+  web:
+    environment:
+      FOO: overridden
+`)
+
+	komposeObject, err := (&Compose{}).LoadFile([]string{base, override})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	web, ok := komposeObject.ServiceConfigs["web"]
+	if !ok {
+		t.Fatal("expected a \"web\" service")
+	}
+	if len(web.Environment) != 1 || web.Environment[0].Value != "overridden" {
+		t.Errorf("Environment = %+v, want FOO=overridden", web.Environment)
+	}
+}
+
+func TestLoadFileInterpolation(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "docker-compose.yml", `
+services:
+  web:
+    image: "nginx:${TAG:-latest}"
+`)
+
+	komposeObject, err := (&Compose{}).LoadFile([]string{file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := komposeObject.ServiceConfigs["web"].Image; got != "nginx:latest" {
+		t.Errorf("Image = %q, want %q", got, "nginx:latest")
+	}
+}
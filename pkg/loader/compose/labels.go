@@ -0,0 +1,153 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// nodeSelectorLabelPrefix is the prefix used by the per-key node selector
+// label family, e.g. "kompose.node-selector.disktype=ssd".
+const nodeSelectorLabelPrefix = "kompose.node-selector."
+
+// xFieldPrefix is the top-level-extension equivalent of the "kompose."
+// label prefix, e.g. "x-kompose-service-account-name".
+const xFieldPrefix = "x-kompose-"
+
+// komposeLabelHandlers is the central registry of recognized
+// "kompose.*" labels (and their "x-kompose-*" extension-field
+// equivalents). Adding support for a new label means adding one entry
+// here; anything under the "kompose." prefix that isn't registered is
+// rejected by applyKomposeLabels/applyKomposeExtensions instead of
+// silently becoming an annotation.
+var komposeLabelHandlers = map[string]func(serviceConfig *kobject.ServiceConfig, value string) error{
+	"kompose.service.type": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceType, err := handleServiceType(value)
+		if err != nil {
+			return err
+		}
+		serviceConfig.ServiceType = serviceType
+		return nil
+	},
+	"kompose.image-pull-secret": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceConfig.ImagePullSecret = value
+		return nil
+	},
+	"kompose.image-pull-policy": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceConfig.ImagePullPolicy = value
+		return nil
+	},
+	"kompose.service-account-name": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceConfig.ServiceAccountName = value
+		return nil
+	},
+	"kompose.expose": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceConfig.ExposeService = value
+		return nil
+	},
+	"kompose.controller.type": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		serviceConfig.ControllerType = value
+		return nil
+	},
+	"kompose.hpa.min": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		ensureHPA(serviceConfig).Min = value
+		return nil
+	},
+	"kompose.hpa.max": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		ensureHPA(serviceConfig).Max = value
+		return nil
+	},
+	"kompose.hpa.cpu-target": func(serviceConfig *kobject.ServiceConfig, value string) error {
+		ensureHPA(serviceConfig).CPUTarget = value
+		return nil
+	},
+}
+
+// ensureHPA returns serviceConfig.HPA, allocating it on first use.
+func ensureHPA(serviceConfig *kobject.ServiceConfig) *kobject.HorizontalPodAutoscaler {
+	if serviceConfig.HPA == nil {
+		serviceConfig.HPA = &kobject.HorizontalPodAutoscaler{}
+	}
+	return serviceConfig.HPA
+}
+
+// applyKomposeLabels walks a service's labels, applying every recognized
+// "kompose.*" key to serviceConfig via komposeLabelHandlers and every
+// "kompose.node-selector.<key>" entry into serviceConfig.NodeSelector.
+// It returns an error naming the first unrecognized "kompose.*" key, so
+// typos surface at load time instead of silently becoming a pod
+// annotation.
+func applyKomposeLabels(serviceConfig *kobject.ServiceConfig, labels map[string]string) error {
+	for key, value := range labels {
+		if strings.HasPrefix(key, nodeSelectorLabelPrefix) {
+			if serviceConfig.NodeSelector == nil {
+				serviceConfig.NodeSelector = map[string]string{}
+			}
+			serviceConfig.NodeSelector[strings.TrimPrefix(key, nodeSelectorLabelPrefix)] = value
+			continue
+		}
+
+		if !strings.HasPrefix(key, "kompose.") {
+			continue
+		}
+
+		handler, ok := komposeLabelHandlers[key]
+		if !ok {
+			return fmt.Errorf("unknown kompose label %q", key)
+		}
+		if err := handler(serviceConfig, value); err != nil {
+			return fmt.Errorf("%q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// applyKomposeExtensions mirrors applyKomposeLabels for the "x-kompose-*"
+// top-level extension fields a service may set instead of (or alongside)
+// labels, e.g. `x-kompose-service-account-name: my-sa`.
+func applyKomposeExtensions(serviceConfig *kobject.ServiceConfig, extensions map[string]interface{}) error {
+	for key, value := range extensions {
+		if !strings.HasPrefix(key, xFieldPrefix) {
+			continue
+		}
+		labelKey := "kompose." + strings.TrimPrefix(key, xFieldPrefix)
+		strValue, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("extension field %q must be a string", key)
+		}
+
+		if strings.HasPrefix(labelKey, nodeSelectorLabelPrefix) {
+			if serviceConfig.NodeSelector == nil {
+				serviceConfig.NodeSelector = map[string]string{}
+			}
+			serviceConfig.NodeSelector[strings.TrimPrefix(labelKey, nodeSelectorLabelPrefix)] = strValue
+			continue
+		}
+
+		handler, ok := komposeLabelHandlers[labelKey]
+		if !ok {
+			return fmt.Errorf("unknown extension field %q", key)
+		}
+		if err := handler(serviceConfig, strValue); err != nil {
+			return fmt.Errorf("%q: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func TestApplyKomposeLabels(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeLabels(serviceConfig, map[string]string{
+		"kompose.service.type":         "NodePort",
+		"kompose.image-pull-secret":    "my-secret",
+		"kompose.image-pull-policy":    "Always",
+		"kompose.service-account-name": "my-sa",
+		"kompose.node-selector.disktype": "ssd",
+		"kompose.controller.type":      "StatefulSet",
+		"kompose.hpa.min":              "1",
+		"kompose.hpa.max":              "5",
+		"kompose.hpa.cpu-target":       "80",
+		"some.other.label":             "ignored",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if serviceConfig.ImagePullSecret != "my-secret" {
+		t.Errorf("ImagePullSecret = %q, want %q", serviceConfig.ImagePullSecret, "my-secret")
+	}
+	if serviceConfig.ImagePullPolicy != "Always" {
+		t.Errorf("ImagePullPolicy = %q, want %q", serviceConfig.ImagePullPolicy, "Always")
+	}
+	if serviceConfig.ServiceAccountName != "my-sa" {
+		t.Errorf("ServiceAccountName = %q, want %q", serviceConfig.ServiceAccountName, "my-sa")
+	}
+	if serviceConfig.NodeSelector["disktype"] != "ssd" {
+		t.Errorf("NodeSelector[disktype] = %q, want %q", serviceConfig.NodeSelector["disktype"], "ssd")
+	}
+	if serviceConfig.ControllerType != "StatefulSet" {
+		t.Errorf("ControllerType = %q, want %q", serviceConfig.ControllerType, "StatefulSet")
+	}
+	if serviceConfig.HPA == nil || serviceConfig.HPA.Min != "1" || serviceConfig.HPA.Max != "5" || serviceConfig.HPA.CPUTarget != "80" {
+		t.Errorf("HPA = %+v, want {Min:1 Max:5 CPUTarget:80}", serviceConfig.HPA)
+	}
+}
+
+func TestApplyKomposeLabelsUnknownKey(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeLabels(serviceConfig, map[string]string{
+		"kompose.sercvice.type": "NodePort", // typo
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unrecognized kompose.* label, got none")
+	}
+}
+
+func TestApplyKomposeExtensionsUnknownKey(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeExtensions(serviceConfig, map[string]interface{}{
+		"x-kompose-not-a-real-field": "value",
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unrecognized x-kompose-* extension, got none")
+	}
+}
+
+func TestApplyKomposeExtensions(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeExtensions(serviceConfig, map[string]interface{}{
+		"x-kompose-service-account-name": "my-sa",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if serviceConfig.ServiceAccountName != "my-sa" {
+		t.Errorf("ServiceAccountName = %q, want %q", serviceConfig.ServiceAccountName, "my-sa")
+	}
+}
+
+func TestApplyKomposeExtensionsNodeSelector(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeExtensions(serviceConfig, map[string]interface{}{
+		"x-kompose-node-selector.disktype": "ssd",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if serviceConfig.NodeSelector["disktype"] != "ssd" {
+		t.Errorf("NodeSelector[disktype] = %q, want %q", serviceConfig.NodeSelector["disktype"], "ssd")
+	}
+}
+
+func TestApplyKomposeExtensionsServiceTypeError(t *testing.T) {
+	serviceConfig := &kobject.ServiceConfig{}
+	err := applyKomposeExtensions(serviceConfig, map[string]interface{}{
+		"x-kompose-service.type": "bogus",
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid service type, got none")
+	}
+}
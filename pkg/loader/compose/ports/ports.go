@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ports translates compose-go's already-parsed port entries
+// (covering both the short "HOST:CONTAINER/proto" form and the long
+// target/published/protocol/mode form, including port ranges) into
+// kobject.Ports.
+//
+// compose-go itself expands a symmetric range like "3000-3005:3000-3005"
+// into one ServicePortConfig per port pair before this package ever sees
+// it, since Target is a single uint32 and can't carry a range. The one
+// range shape that survives to here is the asymmetric "HOST_RANGE:PORT"
+// form (e.g. "8000-9000:80"): Docker picks one free host port from the
+// range at container-start time, which Kubernetes has no equivalent for,
+// so we pin to the first port in the range.
+package ports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// Parse converts a service's compose-go port entries into kobject.Ports.
+func Parse(composePorts []types.ServicePortConfig) ([]kobject.Ports, error) {
+	result := []kobject.Ports{}
+	for _, p := range composePorts {
+		port, err := convert(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, port)
+	}
+	return result, nil
+}
+
+// convert turns a single compose-go port entry into one kobject.Ports.
+func convert(p types.ServicePortConfig) (kobject.Ports, error) {
+	var appProto *string
+	if p.AppProtocol != "" {
+		v := p.AppProtocol
+		appProto = &v
+	}
+
+	kp := kobject.Ports{
+		ContainerPort: int32(p.Target),
+		Protocol:      protocol(p.Protocol),
+		HostIP:        p.HostIP,
+		Name:          p.Name,
+		AppProtocol:   appProto,
+	}
+
+	if p.Published == "" {
+		return kp, nil
+	}
+
+	publishedStart, _, err := parseRange(p.Published)
+	if err != nil {
+		return kobject.Ports{}, fmt.Errorf("invalid host port %q: %v", p.Published, err)
+	}
+	kp.HostPort = int32(publishedStart)
+	return kp, nil
+}
+
+// parseRange parses "N" or "N-M" into its bounds; "N" returns (N, N).
+func parseRange(s string) (int, int, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "-") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		return n, n, nil
+	}
+	parts := strings.SplitN(s, "-", 2)
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("range end %d is before range start %d", end, start)
+	}
+	return start, end, nil
+}
+
+func protocol(p string) api.Protocol {
+	if strings.EqualFold("udp", p) {
+		return api.ProtocolUDP
+	}
+	return api.ProtocolTCP
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ports
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func TestParse(t *testing.T) {
+	appProto := "grpc"
+
+	tests := []struct {
+		name    string
+		in      []types.ServicePortConfig
+		want    []kobject.Ports
+		wantErr bool
+	}{
+		{
+			name: "container port only",
+			in:   []types.ServicePortConfig{{Target: 3000}},
+			want: []kobject.Ports{{ContainerPort: 3000, Protocol: api.ProtocolTCP}},
+		},
+		{
+			name: "host and container port",
+			in:   []types.ServicePortConfig{{Target: 8001, Published: "8001"}},
+			want: []kobject.Ports{{HostPort: 8001, ContainerPort: 8001, Protocol: api.ProtocolTCP}},
+		},
+		{
+			name: "host ip binding",
+			in:   []types.ServicePortConfig{{Target: 8001, Published: "8001", HostIP: "127.0.0.1"}},
+			want: []kobject.Ports{{HostPort: 8001, ContainerPort: 8001, Protocol: api.ProtocolTCP, HostIP: "127.0.0.1"}},
+		},
+		{
+			name: "udp protocol",
+			in:   []types.ServicePortConfig{{Target: 53, Published: "53", Protocol: "udp"}},
+			want: []kobject.Ports{{HostPort: 53, ContainerPort: 53, Protocol: api.ProtocolUDP}},
+		},
+		{
+			// "3000-3005:3000-3005": compose-go expands a symmetric
+			// range into one ServicePortConfig per port pair before we
+			// ever see it, since Target is a scalar uint32.
+			name: "symmetric range pre-expanded by compose-go",
+			in: []types.ServicePortConfig{
+				{Target: 3000, Published: "3000"},
+				{Target: 3001, Published: "3001"},
+				{Target: 3002, Published: "3002"},
+			},
+			want: []kobject.Ports{
+				{HostPort: 3000, ContainerPort: 3000, Protocol: api.ProtocolTCP},
+				{HostPort: 3001, ContainerPort: 3001, Protocol: api.ProtocolTCP},
+				{HostPort: 3002, ContainerPort: 3002, Protocol: api.ProtocolTCP},
+			},
+		},
+		{
+			// "8000-9000:80": Docker picks one free host port from the
+			// range at container-start time. Kubernetes has no
+			// equivalent, so we pin to the first port in the range
+			// rather than erroring on the length mismatch.
+			name: "host port range maps to single container port",
+			in:   []types.ServicePortConfig{{Target: 80, Published: "8000-9000"}},
+			want: []kobject.Ports{{HostPort: 8000, ContainerPort: 80, Protocol: api.ProtocolTCP}},
+		},
+		{
+			name: "app protocol and name",
+			in:   []types.ServicePortConfig{{Target: 8080, Published: "8080", AppProtocol: "grpc", Name: "grpc-port"}},
+			want: []kobject.Ports{{HostPort: 8080, ContainerPort: 8080, Protocol: api.ProtocolTCP, AppProtocol: &appProto, Name: "grpc-port"}},
+		},
+		{
+			name:    "malformed published port",
+			in:      []types.ServicePortConfig{{Target: 3000, Published: "not-a-port"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d ports, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.HostPort != w.HostPort || g.ContainerPort != w.ContainerPort || g.Protocol != w.Protocol || g.HostIP != w.HostIP || g.Name != w.Name {
+					t.Errorf("port %d = %+v, want %+v", i, g, w)
+				}
+				if (g.AppProtocol == nil) != (w.AppProtocol == nil) {
+					t.Errorf("port %d AppProtocol = %v, want %v", i, g.AppProtocol, w.AppProtocol)
+				} else if g.AppProtocol != nil && *g.AppProtocol != *w.AppProtocol {
+					t.Errorf("port %d AppProtocol = %v, want %v", i, *g.AppProtocol, *w.AppProtocol)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema type-checks a raw Compose file against an embedded CUE
+// schema before it is handed to a loader's own Parse step. It exists so
+// every loader shares one declarative source of truth for which Compose
+// keys and Kompose labels/x-fields are supported, instead of each loader
+// growing its own scattered CheckUnsupportedKey-style checks.
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	cueyaml "cuelang.org/go/encoding/yaml"
+)
+
+//go:embed compose.cue
+var composeSchema string
+
+// ValidationError is a single schema violation, with enough position
+// information to point a user at the exact line in their compose file.
+type ValidationError struct {
+	File    string
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Path, e.Message)
+}
+
+// Validate type-checks the raw YAML of a compose file against the
+// embedded Compose schema, returning one ValidationError per violation
+// found (e.g. a malformed port string, an unrecognized "kompose.*" label
+// value, or a key of the wrong type). An empty, nil-error result means
+// the file is schema-valid.
+func Validate(file string, data []byte) ([]ValidationError, error) {
+	ctx := cuecontext.New()
+
+	schema := ctx.CompileString(composeSchema, cuecontext.Filename("schema/compose.cue"))
+	if schema.Err() != nil {
+		return nil, fmt.Errorf("invalid embedded schema: %v", schema.Err())
+	}
+
+	doc, err := cueyaml.Extract(file, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s as YAML: %v", file, err)
+	}
+
+	composeValue := ctx.BuildFile(doc)
+	unified := schema.Unify(composeValue)
+
+	if err := unified.Validate(); err == nil {
+		return nil, nil
+	}
+
+	var violations []ValidationError
+	for _, e := range errors.Errors(unified.Validate()) {
+		pos := errors.Positions(e)
+		verr := ValidationError{
+			File:    file,
+			Path:    pathString(e),
+			Message: e.Error(),
+		}
+		if len(pos) > 0 {
+			verr.Line = pos[0].Line()
+			verr.Column = pos[0].Column()
+		}
+		violations = append(violations, verr)
+	}
+	return violations, nil
+}
+
+// pathString renders the CUE field path (e.g. services.web.ports.2) a
+// validation error occurred at, falling back to "" when CUE can't
+// associate the error with a specific path.
+func pathString(e errors.Error) string {
+	path := e.Path()
+	if len(path) == 0 {
+		return ""
+	}
+	out := path[0]
+	for _, p := range path[1:] {
+		out += "." + p
+	}
+	return out
+}
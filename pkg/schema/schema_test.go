@@ -0,0 +1,126 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/kubernetes-incubator/kompose/pkg/loader/compose/ports"
+)
+
+func TestValidateRejectsBadPort(t *testing.T) {
+	data := []byte(`
+services:
+  web:
+    image: nginx
+    ports:
+      - "not-a-port"
+`)
+
+	violations, err := Validate("docker-compose.yml", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) == 0 {
+		t.Fatal("expected a violation for an invalid port string, got none")
+	}
+
+	v := violations[0]
+	if v.File != "docker-compose.yml" {
+		t.Errorf("File = %q, want %q", v.File, "docker-compose.yml")
+	}
+	if v.Line == 0 {
+		t.Error("expected a non-zero line number for the violation")
+	}
+	if v.Path == "" {
+		t.Error("expected a non-empty field path (e.g. services.web.ports.0) for the violation")
+	}
+}
+
+func TestValidateAcceptsBareIntPort(t *testing.T) {
+	data := []byte(`
+services:
+  web:
+    image: nginx
+    ports:
+      - 3000
+`)
+
+	violations, err := Validate("docker-compose.yml", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for a bare int port, got %v", violations)
+	}
+}
+
+func TestValidateAcceptsHostIPPort(t *testing.T) {
+	data := []byte(`
+services:
+  web:
+    image: nginx
+    ports:
+      - "127.0.0.1:8001:8001"
+      - "3000-3005:3000-3005"
+`)
+
+	violations, err := Validate("docker-compose.yml", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for valid host-ip/range ports, got %v", violations)
+	}
+
+	// Confirm the file that just passed schema validation also parses
+	// cleanly through the real loader and ports.Parse, so the schema
+	// isn't accidentally accepting a form the rest of the pipeline
+	// rejects.
+	details := types.ConfigDetails{
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Content: data}},
+	}
+	project, err := loader.LoadWithContext(context.Background(), details, func(o *loader.Options) {
+		o.SkipValidation = true
+	})
+	if err != nil {
+		t.Fatalf("failed to load compose file: %v", err)
+	}
+
+	svc, ok := project.Services["web"]
+	if !ok {
+		t.Fatal("expected a \"web\" service")
+	}
+
+	parsed, err := ports.Parse(svc.Ports)
+	if err != nil {
+		t.Fatalf("ports.Parse failed: %v", err)
+	}
+
+	var sawHostIP bool
+	for _, p := range parsed {
+		if p.HostIP == "127.0.0.1" {
+			sawHostIP = true
+		}
+	}
+	if !sawHostIP {
+		t.Errorf("expected a parsed port with HostIP 127.0.0.1, got %+v", parsed)
+	}
+}
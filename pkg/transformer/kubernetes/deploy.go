@@ -0,0 +1,216 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/util/intstr"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// ApplyResources sets the service's (first) container's resource
+// requests/limits from `deploy.resources`. Returns an error if either
+// the limits or the requests contain a CPU/memory value Kubernetes'
+// resource.Quantity grammar can't parse.
+func ApplyResources(podSpec *api.PodSpec, service kobject.ServiceConfig) error {
+	if len(podSpec.Containers) == 0 {
+		return nil
+	}
+
+	requirements := api.ResourceRequirements{}
+	limits, err := resourceList(service.Resources.Limits)
+	if err != nil {
+		return fmt.Errorf("deploy.resources.limits: %v", err)
+	}
+	if len(limits) > 0 {
+		requirements.Limits = limits
+	}
+	requests, err := resourceList(service.Resources.Requests)
+	if err != nil {
+		return fmt.Errorf("deploy.resources.reservations: %v", err)
+	}
+	if len(requests) > 0 {
+		requirements.Requests = requests
+	}
+	podSpec.Containers[0].Resources = requirements
+	return nil
+}
+
+func resourceList(r kobject.Resource) (api.ResourceList, error) {
+	list := api.ResourceList{}
+	if r.CPU != "" {
+		q, err := resource.ParseQuantity(r.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("cpus %q: %v", r.CPU, err)
+		}
+		list[api.ResourceCPU] = q
+	}
+	if r.Memory != "" {
+		q, err := resource.ParseQuantity(r.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("memory %q: %v", r.Memory, err)
+		}
+		list[api.ResourceMemory] = q
+	}
+	return list, nil
+}
+
+// restartPolicyFor maps a `deploy.restart_policy.condition` onto the pod
+// spec's `restartPolicy`.
+func restartPolicyFor(condition string) api.RestartPolicy {
+	switch condition {
+	case "none":
+		return api.RestartPolicyNever
+	case "on-failure":
+		return api.RestartPolicyOnFailure
+	default:
+		return api.RestartPolicyAlways
+	}
+}
+
+// ApplyRestartPolicy sets podSpec.RestartPolicy from
+// `deploy.restart_policy.condition`, when the service sets one.
+func ApplyRestartPolicy(podSpec *api.PodSpec, service kobject.ServiceConfig) {
+	if service.RestartPolicy == nil {
+		return
+	}
+	podSpec.RestartPolicy = restartPolicyFor(service.RestartPolicy.Condition)
+}
+
+// ApplyPlacement translates `deploy.placement.constraints` entries of the
+// form "node.labels.<key>==<value>" into podSpec.NodeSelector. Any other
+// constraint form (e.g. "!=" exclusions) isn't representable as a plain
+// nodeSelector and is skipped without a warning; no nodeAffinity is
+// generated for it.
+func ApplyPlacement(podSpec *api.PodSpec, service kobject.ServiceConfig) {
+	for _, constraint := range service.PlacementConstraints {
+		key, value, ok := parseLabelConstraint(constraint)
+		if !ok {
+			continue
+		}
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		podSpec.NodeSelector[key] = value
+	}
+}
+
+func parseLabelConstraint(constraint string) (key, value string, ok bool) {
+	parts := strings.SplitN(constraint, "==", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	raw := strings.TrimSpace(parts[0])
+	label := strings.TrimPrefix(raw, "node.labels.")
+	label = strings.TrimPrefix(label, "engine.labels.")
+	if label == raw {
+		// not a recognized "<scope>.labels.<key>" constraint
+		return "", "", false
+	}
+	return label, strings.TrimSpace(parts[1]), true
+}
+
+// DeploymentStrategy translates `deploy.update_config` into the
+// workload's `strategy`. "stop-first" maps onto a Recreate strategy (all
+// old pods are torn down before new ones come up, matching Swarm's
+// semantics); everything else (including the default "start-first") maps
+// onto RollingUpdate. Returns the zero value (the cluster's own default
+// strategy) when the service has no update_config.
+func DeploymentStrategy(service kobject.ServiceConfig) extensions.DeploymentStrategy {
+	if service.UpdateConfig == nil {
+		return extensions.DeploymentStrategy{}
+	}
+
+	if service.UpdateConfig.Order == "stop-first" {
+		return extensions.DeploymentStrategy{Type: extensions.RecreateDeploymentStrategyType}
+	}
+
+	rollingUpdate := &extensions.RollingUpdateDeployment{}
+	if service.UpdateConfig.Parallelism != nil {
+		surge := intstr.FromInt(int(*service.UpdateConfig.Parallelism))
+		rollingUpdate.MaxSurge = surge
+	}
+
+	return extensions.DeploymentStrategy{
+		Type:          extensions.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: rollingUpdate,
+	}
+}
+
+// ClusterIPFor returns "None" when `deploy.endpoint_mode` is "dnsrr"
+// (a headless Service, one DNS record per pod instead of a single
+// virtual IP), and "" otherwise.
+func ClusterIPFor(service kobject.ServiceConfig) string {
+	if strings.EqualFold(service.EndpointMode, "dnsrr") {
+		return "None"
+	}
+	return ""
+}
+
+// WorkloadLabels returns the labels that belong on the workload's own
+// ObjectMeta (Deployment/StatefulSet/...), as opposed to its pod
+// template: `deploy.labels`, distinct from the service-level `labels:`
+// that become pod annotations (see applyKomposeLabels's caller).
+func WorkloadLabels(service kobject.ServiceConfig) map[string]string {
+	return service.DeployLabels
+}
+
+// CreateHPA builds the HorizontalPodAutoscaler for a service whose
+// `kompose.hpa.*` labels are set. It requires both `deploy.replicas` (as
+// the scale target's starting point) and a resource reservation (the
+// CPU utilization target needs a CPU request to scale against), per the
+// "deploy.replicas combined with deploy.resources.reservations" trigger.
+// Returns nil when either precondition isn't met.
+func CreateHPA(name string, service kobject.ServiceConfig) *autoscaling.HorizontalPodAutoscaler {
+	if service.HPA == nil || service.Replicas == nil || service.Resources.Requests.CPU == "" {
+		return nil
+	}
+
+	hpa := &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Spec: autoscaling.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscaling.CrossVersionObjectReference{
+				Kind: ControllerKind(service),
+				Name: name,
+			},
+			MinReplicas: service.Replicas,
+			MaxReplicas: *service.Replicas,
+		},
+	}
+
+	if min, err := strconv.Atoi(service.HPA.Min); err == nil {
+		minReplicas := int32(min)
+		hpa.Spec.MinReplicas = &minReplicas
+	}
+	if max, err := strconv.Atoi(service.HPA.Max); err == nil {
+		hpa.Spec.MaxReplicas = int32(max)
+	}
+	if target, err := strconv.Atoi(service.HPA.CPUTarget); err == nil {
+		targetPercentage := int32(target)
+		hpa.Spec.TargetCPUUtilizationPercentage = &targetPercentage
+	}
+
+	return hpa
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestApplyResources(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	service := kobject.ServiceConfig{
+		Resources: kobject.ResourceConfig{
+			Limits:   kobject.Resource{CPU: "500m", Memory: "256Mi"},
+			Requests: kobject.Resource{CPU: "250m", Memory: "128Mi"},
+		},
+	}
+
+	if err := ApplyResources(&podSpec, service); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	limits := podSpec.Containers[0].Resources.Limits
+	if limits.Cpu().String() != "500m" || limits.Memory().String() != "256Mi" {
+		t.Errorf("Limits = %+v", limits)
+	}
+	requests := podSpec.Containers[0].Resources.Requests
+	if requests.Cpu().String() != "250m" || requests.Memory().String() != "128Mi" {
+		t.Errorf("Requests = %+v", requests)
+	}
+}
+
+// TestApplyResourcesByteCountMemory exercises the format loadDeploy
+// actually produces: a raw byte count (compose-go's MemoryBytes, via
+// strconv.FormatInt), not a Kubernetes-style "256Mi" literal.
+func TestApplyResourcesByteCountMemory(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	service := kobject.ServiceConfig{
+		Resources: kobject.ResourceConfig{
+			Limits: kobject.Resource{Memory: "134217728"}, // 128 * 1024 * 1024
+		},
+	}
+
+	if err := ApplyResources(&podSpec, service); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := podSpec.Containers[0].Resources.Limits.Memory().String(); got != "134217728" {
+		t.Errorf("Memory = %q, want %q", got, "134217728")
+	}
+}
+
+func TestApplyResourcesInvalidQuantity(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	service := kobject.ServiceConfig{
+		Resources: kobject.ResourceConfig{Limits: kobject.Resource{CPU: "not-a-quantity"}},
+	}
+
+	if err := ApplyResources(&podSpec, service); err == nil {
+		t.Fatal("expected an error for an unparseable CPU quantity, got none")
+	}
+}
+
+func TestApplyRestartPolicy(t *testing.T) {
+	podSpec := api.PodSpec{}
+	ApplyRestartPolicy(&podSpec, kobject.ServiceConfig{RestartPolicy: &kobject.RestartPolicy{Condition: "on-failure"}})
+	if podSpec.RestartPolicy != api.RestartPolicyOnFailure {
+		t.Errorf("RestartPolicy = %v, want %v", podSpec.RestartPolicy, api.RestartPolicyOnFailure)
+	}
+}
+
+func TestApplyPlacement(t *testing.T) {
+	podSpec := api.PodSpec{}
+	service := kobject.ServiceConfig{PlacementConstraints: []string{"node.labels.disktype==ssd", "node.role!=manager"}}
+
+	ApplyPlacement(&podSpec, service)
+
+	if podSpec.NodeSelector["disktype"] != "ssd" {
+		t.Errorf("NodeSelector[disktype] = %q, want %q", podSpec.NodeSelector["disktype"], "ssd")
+	}
+	if len(podSpec.NodeSelector) != 1 {
+		t.Errorf("NodeSelector = %+v, want exactly one entry (the \"!=\" constraint isn't representable)", podSpec.NodeSelector)
+	}
+}
+
+func TestDeploymentStrategy(t *testing.T) {
+	if got := DeploymentStrategy(kobject.ServiceConfig{}); got.Type != "" {
+		t.Errorf("expected zero-value strategy with no update_config, got %+v", got)
+	}
+
+	service := kobject.ServiceConfig{UpdateConfig: &kobject.UpdateConfig{Parallelism: uint64Ptr(2)}}
+	strategy := DeploymentStrategy(service)
+	if strategy.Type != extensions.RollingUpdateDeploymentStrategyType {
+		t.Errorf("Type = %v, want %v", strategy.Type, extensions.RollingUpdateDeploymentStrategyType)
+	}
+	if strategy.RollingUpdate.MaxSurge.IntValue() != 2 {
+		t.Errorf("MaxSurge = %v, want 2", strategy.RollingUpdate.MaxSurge)
+	}
+}
+
+func TestDeploymentStrategyStopFirst(t *testing.T) {
+	service := kobject.ServiceConfig{UpdateConfig: &kobject.UpdateConfig{Order: "stop-first"}}
+	strategy := DeploymentStrategy(service)
+	if strategy.Type != extensions.RecreateDeploymentStrategyType {
+		t.Errorf("Type = %v, want %v", strategy.Type, extensions.RecreateDeploymentStrategyType)
+	}
+	if strategy.RollingUpdate != nil {
+		t.Errorf("RollingUpdate = %+v, want nil for a Recreate strategy", strategy.RollingUpdate)
+	}
+}
+
+func uint64Ptr(i uint64) *uint64 { return &i }
+
+func TestClusterIPFor(t *testing.T) {
+	if got := ClusterIPFor(kobject.ServiceConfig{EndpointMode: "dnsrr"}); got != "None" {
+		t.Errorf("ClusterIPFor(dnsrr) = %q, want %q", got, "None")
+	}
+	if got := ClusterIPFor(kobject.ServiceConfig{}); got != "" {
+		t.Errorf("ClusterIPFor() = %q, want empty", got)
+	}
+}
+
+func TestCreateHPA(t *testing.T) {
+	service := kobject.ServiceConfig{
+		Replicas:  int32Ptr(3),
+		Resources: kobject.ResourceConfig{Requests: kobject.Resource{CPU: "250m"}},
+		HPA:       &kobject.HorizontalPodAutoscaler{Min: "2", Max: "10", CPUTarget: "75"},
+	}
+
+	hpa := CreateHPA("web", service)
+	if hpa == nil {
+		t.Fatal("expected an HPA, got nil")
+	}
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 10 || *hpa.Spec.TargetCPUUtilizationPercentage != 75 {
+		t.Errorf("Spec = %+v", hpa.Spec)
+	}
+}
+
+func TestCreateHPAScaleTargetKind(t *testing.T) {
+	service := kobject.ServiceConfig{
+		ControllerType: "StatefulSet",
+		Replicas:       int32Ptr(3),
+		Resources:      kobject.ResourceConfig{Requests: kobject.Resource{CPU: "250m"}},
+		HPA:            &kobject.HorizontalPodAutoscaler{Min: "2", Max: "10", CPUTarget: "75"},
+	}
+
+	hpa := CreateHPA("web", service)
+	if hpa == nil {
+		t.Fatal("expected an HPA, got nil")
+	}
+	if hpa.Spec.ScaleTargetRef.Kind != "StatefulSet" {
+		t.Errorf("ScaleTargetRef.Kind = %q, want %q", hpa.Spec.ScaleTargetRef.Kind, "StatefulSet")
+	}
+}
+
+func TestCreateHPANoResourceRequest(t *testing.T) {
+	service := kobject.ServiceConfig{
+		Replicas: int32Ptr(3),
+		HPA:      &kobject.HorizontalPodAutoscaler{Min: "2", Max: "10", CPUTarget: "75"},
+	}
+	if got := CreateHPA("web", service); got != nil {
+		t.Errorf("expected no HPA without a CPU resource request, got %+v", got)
+	}
+}
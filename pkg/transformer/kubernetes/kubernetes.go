@@ -0,0 +1,84 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubernetes transforms a kobject.KomposeObject into the
+// Kubernetes API objects "kompose convert" writes out.
+package kubernetes
+
+import (
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// defaultControllerType is the workload kind generated for a service
+// whose "kompose.controller.type" label isn't set.
+const defaultControllerType = "Deployment"
+
+// ControllerKind returns the workload kind kompose should generate for a
+// service: "kompose.controller.type" if the label is set, else
+// "Deployment".
+func ControllerKind(service kobject.ServiceConfig) string {
+	if service.ControllerType != "" {
+		return service.ControllerType
+	}
+	return defaultControllerType
+}
+
+// InitPodSpec builds the base v1.PodSpec for a service's container,
+// applying the pod-spec overrides parsed from "kompose.*" labels and
+// "x-kompose-*" extensions: image pull secret/policy, service account,
+// and node selector.
+func InitPodSpec(name string, service kobject.ServiceConfig) api.PodSpec {
+	container := api.Container{
+		Name:  name,
+		Image: service.Image,
+	}
+	if service.ImagePullPolicy != "" {
+		container.ImagePullPolicy = api.PullPolicy(service.ImagePullPolicy)
+	}
+
+	podSpec := api.PodSpec{
+		Containers: []api.Container{container},
+	}
+
+	if service.ImagePullSecret != "" {
+		podSpec.ImagePullSecrets = []api.LocalObjectReference{{Name: service.ImagePullSecret}}
+	}
+	if service.ServiceAccountName != "" {
+		podSpec.ServiceAccountName = service.ServiceAccountName
+	}
+	if len(service.NodeSelector) > 0 {
+		podSpec.NodeSelector = service.NodeSelector
+	}
+
+	return podSpec
+}
+
+// ShouldExpose reports whether the "kompose.expose" label asks kompose to
+// generate an Ingress for this service, and the host to route on (empty
+// when the label is just "true").
+func ShouldExpose(service kobject.ServiceConfig) (host string, expose bool) {
+	if service.ExposeService == "" {
+		return "", false
+	}
+	if strings.EqualFold(service.ExposeService, "true") {
+		return "", true
+	}
+	return service.ExposeService, true
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func TestInitPodSpec(t *testing.T) {
+	service := kobject.ServiceConfig{
+		Image:              "nginx",
+		ImagePullSecret:    "regcred",
+		ImagePullPolicy:    "Always",
+		ServiceAccountName: "my-sa",
+		NodeSelector:       map[string]string{"disktype": "ssd"},
+	}
+
+	podSpec := InitPodSpec("web", service)
+
+	if len(podSpec.Containers) != 1 || podSpec.Containers[0].Image != "nginx" {
+		t.Fatalf("containers = %+v", podSpec.Containers)
+	}
+	if podSpec.Containers[0].ImagePullPolicy != api.PullAlways {
+		t.Errorf("ImagePullPolicy = %v, want %v", podSpec.Containers[0].ImagePullPolicy, api.PullAlways)
+	}
+	if len(podSpec.ImagePullSecrets) != 1 || podSpec.ImagePullSecrets[0].Name != "regcred" {
+		t.Errorf("ImagePullSecrets = %+v", podSpec.ImagePullSecrets)
+	}
+	if podSpec.ServiceAccountName != "my-sa" {
+		t.Errorf("ServiceAccountName = %q, want %q", podSpec.ServiceAccountName, "my-sa")
+	}
+	if podSpec.NodeSelector["disktype"] != "ssd" {
+		t.Errorf("NodeSelector[disktype] = %q, want %q", podSpec.NodeSelector["disktype"], "ssd")
+	}
+}
+
+func TestControllerKind(t *testing.T) {
+	if got := ControllerKind(kobject.ServiceConfig{}); got != "Deployment" {
+		t.Errorf("ControllerKind() = %q, want %q", got, "Deployment")
+	}
+	if got := ControllerKind(kobject.ServiceConfig{ControllerType: "DaemonSet"}); got != "DaemonSet" {
+		t.Errorf("ControllerKind() = %q, want %q", got, "DaemonSet")
+	}
+}
+
+func TestShouldExpose(t *testing.T) {
+	if _, expose := ShouldExpose(kobject.ServiceConfig{}); expose {
+		t.Error("expected ShouldExpose to be false with no kompose.expose label")
+	}
+	if host, expose := ShouldExpose(kobject.ServiceConfig{ExposeService: "true"}); !expose || host != "" {
+		t.Errorf("ShouldExpose(true) = (%q, %v), want (\"\", true)", host, expose)
+	}
+	if host, expose := ShouldExpose(kobject.ServiceConfig{ExposeService: "example.com"}); !expose || host != "example.com" {
+		t.Errorf("ShouldExpose(example.com) = (%q, %v), want (\"example.com\", true)", host, expose)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/batch"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/intstr"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// selectorLabels returns the label set used to both tag a workload's pod
+// template and select it from its Service, so the two always agree.
+func selectorLabels(name string) map[string]string {
+	return map[string]string{"service": name}
+}
+
+// CreateService builds the Service fronting a service's ports. Returns
+// nil when the service exposes no ports at all.
+func CreateService(name string, service kobject.ServiceConfig) *api.Service {
+	if len(service.Port) == 0 {
+		return nil
+	}
+
+	ports := make([]api.ServicePort, 0, len(service.Port))
+	for _, p := range service.Port {
+		portName := p.Name
+		if portName == "" && len(service.Port) > 1 {
+			portName = fmt.Sprintf("%s-%d", strings.ToLower(string(p.Protocol)), p.ContainerPort)
+		}
+		ports = append(ports, api.ServicePort{
+			Name:       portName,
+			Port:       p.ContainerPort,
+			TargetPort: intstr.FromInt(int(p.ContainerPort)),
+			Protocol:   p.Protocol,
+		})
+	}
+
+	svc := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Spec: api.ServiceSpec{
+			Selector:  selectorLabels(name),
+			Ports:     ports,
+			ClusterIP: ClusterIPFor(service),
+		},
+	}
+	if service.ServiceType != "" {
+		svc.Spec.Type = api.ServiceType(service.ServiceType)
+	}
+	return svc
+}
+
+// Transform builds the full set of Kubernetes objects for a single
+// compose service: its workload (Deployment/StatefulSet/DaemonSet/Job,
+// chosen by ControllerKind), its Service (if it exposes ports) and its
+// HorizontalPodAutoscaler (if `kompose.hpa.*` is set). The workload's pod
+// spec is assembled from every `deploy:`/label-driven helper in this
+// package, so a service using named volumes, configs/secrets, resource
+// limits, a restart policy and placement constraints gets all of them in
+// one pass.
+func Transform(name string, service kobject.ServiceConfig, komposeObject kobject.KomposeObject) ([]runtime.Object, error) {
+	podSpec := InitPodSpec(name, service)
+	ApplyNamedVolumes(&podSpec, service, komposeObject.NamedVolumes)
+	ApplyConfigsAndSecrets(&podSpec, service, komposeObject.ConfigMaps, komposeObject.Secrets)
+	if err := ApplyResources(&podSpec, service); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	ApplyRestartPolicy(&podSpec, service)
+	ApplyPlacement(&podSpec, service)
+
+	podTemplate := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels:      selectorLabels(name),
+			Annotations: service.Annotations,
+		},
+		Spec: podSpec,
+	}
+
+	replicas := int32(1)
+	if service.Replicas != nil {
+		replicas = *service.Replicas
+	}
+
+	workloadMeta := api.ObjectMeta{Name: name, Labels: WorkloadLabels(service)}
+
+	var objects []runtime.Object
+	switch ControllerKind(service) {
+	case "StatefulSet":
+		objects = append(objects, &apps.StatefulSet{
+			ObjectMeta: workloadMeta,
+			Spec: apps.StatefulSetSpec{
+				Replicas:    replicas,
+				ServiceName: name,
+				Template:    podTemplate,
+			},
+		})
+	case "DaemonSet":
+		objects = append(objects, &extensions.DaemonSet{
+			ObjectMeta: workloadMeta,
+			Spec:       extensions.DaemonSetSpec{Template: podTemplate},
+		})
+	case "Job":
+		objects = append(objects, &batch.Job{
+			ObjectMeta: workloadMeta,
+			Spec:       batch.JobSpec{Template: podTemplate},
+		})
+	default:
+		objects = append(objects, &extensions.Deployment{
+			ObjectMeta: workloadMeta,
+			Spec: extensions.DeploymentSpec{
+				Replicas: replicas,
+				Strategy: DeploymentStrategy(service),
+				Template: podTemplate,
+			},
+		})
+	}
+
+	if svc := CreateService(name, service); svc != nil {
+		objects = append(objects, svc)
+	}
+	if hpa := CreateHPA(name, service); hpa != nil {
+		objects = append(objects, hpa)
+	}
+
+	return objects, nil
+}
+
+// CreateKubernetesObjects transforms an entire KomposeObject into the
+// objects "kompose convert" writes out: every service's workload/Service/
+// HPA (via Transform), plus one PersistentVolumeClaim/ConfigMap/Secret
+// for each top-level named volume/config/secret that isn't external.
+func CreateKubernetesObjects(komposeObject kobject.KomposeObject) ([]runtime.Object, error) {
+	var objects []runtime.Object
+
+	for name, service := range komposeObject.ServiceConfigs {
+		serviceObjects, err := Transform(name, service, komposeObject)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, serviceObjects...)
+	}
+
+	for name, volume := range komposeObject.NamedVolumes {
+		if pvc := CreatePVC(name, volume); pvc != nil {
+			objects = append(objects, pvc)
+		}
+	}
+	for name, config := range komposeObject.ConfigMaps {
+		if cm := CreateConfigMap(name, config); cm != nil {
+			objects = append(objects, cm)
+		}
+	}
+	for name, secret := range komposeObject.Secrets {
+		if s := CreateSecret(name, secret); s != nil {
+			objects = append(objects, s)
+		}
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func TestCreateServiceNoPorts(t *testing.T) {
+	if got := CreateService("web", kobject.ServiceConfig{}); got != nil {
+		t.Errorf("expected no Service without ports, got %+v", got)
+	}
+}
+
+func TestCreateService(t *testing.T) {
+	service := kobject.ServiceConfig{
+		ServiceType: "NodePort",
+		Port:        []kobject.Ports{{ContainerPort: 80, Protocol: api.ProtocolTCP}},
+	}
+
+	svc := CreateService("web", service)
+	if svc == nil {
+		t.Fatal("expected a Service, got nil")
+	}
+	if svc.Spec.Type != api.ServiceTypeNodePort {
+		t.Errorf("Type = %v, want %v", svc.Spec.Type, api.ServiceTypeNodePort)
+	}
+	if svc.Spec.Selector["service"] != "web" {
+		t.Errorf("Selector = %+v, want service=web", svc.Spec.Selector)
+	}
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 80 {
+		t.Errorf("Ports = %+v", svc.Spec.Ports)
+	}
+}
+
+func TestTransformDeployment(t *testing.T) {
+	service := kobject.ServiceConfig{Image: "nginx", Port: []kobject.Ports{{ContainerPort: 80, Protocol: api.ProtocolTCP}}}
+
+	objects, err := Transform("web", service, kobject.KomposeObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deployment *extensions.Deployment
+	var svc *api.Service
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *extensions.Deployment:
+			deployment = o
+		case *api.Service:
+			svc = o
+		}
+	}
+	if deployment == nil {
+		t.Fatal("expected a Deployment among the transformed objects")
+	}
+	if deployment.Spec.Template.Spec.Containers[0].Image != "nginx" {
+		t.Errorf("Image = %q, want %q", deployment.Spec.Template.Spec.Containers[0].Image, "nginx")
+	}
+	if svc == nil {
+		t.Fatal("expected a Service among the transformed objects")
+	}
+}
+
+func TestTransformControllerKind(t *testing.T) {
+	service := kobject.ServiceConfig{Image: "postgres", ControllerType: "StatefulSet"}
+
+	objects, err := Transform("db", service, kobject.KomposeObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := false
+	for _, obj := range objects {
+		if _, ok := obj.(*apps.StatefulSet); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a StatefulSet among the transformed objects, got %+v", objects)
+	}
+}
+
+func TestTransformResourceError(t *testing.T) {
+	service := kobject.ServiceConfig{
+		Image:     "nginx",
+		Resources: kobject.ResourceConfig{Limits: kobject.Resource{CPU: "not-a-quantity"}},
+	}
+
+	if _, err := Transform("web", service, kobject.KomposeObject{}); err == nil {
+		t.Fatal("expected an error for an unparseable resource quantity, got none")
+	}
+}
+
+func TestCreateKubernetesObjects(t *testing.T) {
+	komposeObject := kobject.KomposeObject{
+		ServiceConfigs: map[string]kobject.ServiceConfig{
+			"web": {Image: "nginx"},
+		},
+		NamedVolumes: map[string]kobject.NamedVolumeConfig{
+			"data": {},
+		},
+	}
+
+	objects, err := CreateKubernetesObjects(komposeObject)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sawDeployment, sawPVC bool
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *extensions.Deployment:
+			sawDeployment = true
+		case *api.PersistentVolumeClaim:
+			sawPVC = true
+		}
+	}
+	if !sawDeployment {
+		t.Error("expected a Deployment for the \"web\" service")
+	}
+	if !sawPVC {
+		t.Error("expected a PersistentVolumeClaim for the \"data\" volume")
+	}
+}
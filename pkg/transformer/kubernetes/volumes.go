@@ -0,0 +1,185 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+// storageClassAnnotation is how a PVC requests a StorageClass on this
+// api vintage (the typed Spec.StorageClassName field doesn't exist yet).
+const storageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+// CreatePVC builds the PersistentVolumeClaim for a top-level named
+// volume, honoring `driver` (as a StorageClass) and `driver_opts.size`/
+// `driver_opts.storageClass`. External volumes reference a PVC that's
+// expected to already exist, so no object is created for them — see
+// PVCNameFor.
+func CreatePVC(name string, volume kobject.NamedVolumeConfig) *api.PersistentVolumeClaim {
+	if volume.External {
+		return nil
+	}
+
+	pvc := &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes: []api.PersistentVolumeAccessMode{api.ReadWriteOnce},
+		},
+	}
+
+	storageClass := volume.Driver
+	if sc := volume.DriverOpts["storageClass"]; sc != "" {
+		storageClass = sc
+	}
+	if storageClass != "" {
+		pvc.Annotations = map[string]string{storageClassAnnotation: storageClass}
+	}
+
+	if size := volume.DriverOpts["size"]; size != "" {
+		pvc.Spec.Resources = api.ResourceRequirements{
+			Requests: api.ResourceList{api.ResourceStorage: resource.MustParse(size)},
+		}
+	}
+
+	return pvc
+}
+
+// PVCNameFor returns the PVC name a named volume's volumeMount should
+// reference: the volume's own name, or ExternalName when the volume is
+// external and named differently in the cluster.
+func PVCNameFor(volumeName string, volume kobject.NamedVolumeConfig) string {
+	if volume.External && volume.ExternalName != "" {
+		return volume.ExternalName
+	}
+	return volumeName
+}
+
+// ApplyNamedVolumes wires a service's named-volume mounts onto podSpec:
+// one api.Volume backed by the matching PVC, and a matching VolumeMount
+// on the service's (first) container.
+func ApplyNamedVolumes(podSpec *api.PodSpec, service kobject.ServiceConfig, namedVolumes map[string]kobject.NamedVolumeConfig) {
+	for _, v := range service.NamedVolumes {
+		volume, ok := namedVolumes[v.VolumeName]
+		if !ok {
+			continue
+		}
+
+		podSpec.Volumes = append(podSpec.Volumes, api.Volume{
+			Name: v.VolumeName,
+			VolumeSource: api.VolumeSource{
+				PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{
+					ClaimName: PVCNameFor(v.VolumeName, volume),
+					ReadOnly:  v.ReadOnly,
+				},
+			},
+		})
+
+		if len(podSpec.Containers) == 0 {
+			continue
+		}
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, api.VolumeMount{
+			Name:      v.VolumeName,
+			MountPath: v.Target,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+}
+
+// ApplyConfigsAndSecrets wires a service's `configs:`/`secrets:` entries
+// onto podSpec: one api.Volume backed by the matching ConfigMap/Secret,
+// and a matching VolumeMount on the service's (first) container, mounted
+// at Target (or, absent one, at "/<source-name>" the way Compose itself
+// defaults it). Entries naming a config/secret kompose didn't build an
+// object for (e.g. an external one) are skipped.
+func ApplyConfigsAndSecrets(podSpec *api.PodSpec, service kobject.ServiceConfig, configMaps map[string]kobject.FileConfig, secrets map[string]kobject.FileConfig) {
+	applyFileReferences(podSpec, service.Configs, configMaps, func(name string, mode *int32) api.VolumeSource {
+		return api.VolumeSource{ConfigMap: &api.ConfigMapVolumeSource{
+			LocalObjectReference: api.LocalObjectReference{Name: name},
+			DefaultMode:          mode,
+		}}
+	})
+	applyFileReferences(podSpec, service.Secrets, secrets, func(name string, mode *int32) api.VolumeSource {
+		return api.VolumeSource{Secret: &api.SecretVolumeSource{
+			SecretName:  name,
+			DefaultMode: mode,
+		}}
+	})
+}
+
+func applyFileReferences(podSpec *api.PodSpec, refs []kobject.FileReference, known map[string]kobject.FileConfig, volumeSource func(name string, mode *int32) api.VolumeSource) {
+	for _, ref := range refs {
+		fc, ok := known[ref.Source]
+		if !ok || fc.External {
+			continue
+		}
+
+		var mode *int32
+		if ref.Mode != nil {
+			m := int32(*ref.Mode)
+			mode = &m
+		}
+
+		podSpec.Volumes = append(podSpec.Volumes, api.Volume{
+			Name:         ref.Source,
+			VolumeSource: volumeSource(ref.Source, mode),
+		})
+
+		if len(podSpec.Containers) == 0 {
+			continue
+		}
+		target := ref.Target
+		if target == "" {
+			target = "/" + ref.Source
+		}
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, api.VolumeMount{
+			Name:      ref.Source,
+			MountPath: target,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// CreateConfigMap builds the ConfigMap for a top-level `configs:` entry.
+// External configs reference an object expected to already exist, so no
+// object is created for them.
+func CreateConfigMap(name string, config kobject.FileConfig) *api.ConfigMap {
+	if config.External {
+		return nil
+	}
+	return &api.ConfigMap{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Data:       map[string]string{name: string(config.Content)},
+	}
+}
+
+// CreateSecret builds the Secret for a top-level `secrets:` entry.
+// api.Secret.Data is []byte-valued, so it's base64-encoded on the wire
+// automatically when the object is marshaled — no manual encoding here.
+// External secrets reference an object expected to already exist, so no
+// object is created for them.
+func CreateSecret(name string, secret kobject.FileConfig) *api.Secret {
+	if secret.External {
+		return nil
+	}
+	return &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Data:       map[string][]byte{name: secret.Content},
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+
+	"github.com/kubernetes-incubator/kompose/pkg/kobject"
+)
+
+func TestCreatePVC(t *testing.T) {
+	pvc := CreatePVC("data", kobject.NamedVolumeConfig{
+		Driver:     "standard",
+		DriverOpts: map[string]string{"size": "5Gi"},
+	})
+	if pvc == nil {
+		t.Fatal("expected a PVC, got nil")
+	}
+	if pvc.Annotations[storageClassAnnotation] != "standard" {
+		t.Errorf("storage class annotation = %q, want %q", pvc.Annotations[storageClassAnnotation], "standard")
+	}
+	if pvc.Spec.Resources.Requests[api.ResourceStorage].String() != "5Gi" {
+		t.Errorf("requested storage = %v, want 5Gi", pvc.Spec.Resources.Requests[api.ResourceStorage])
+	}
+}
+
+func TestCreatePVCExternal(t *testing.T) {
+	pvc := CreatePVC("data", kobject.NamedVolumeConfig{External: true})
+	if pvc != nil {
+		t.Errorf("expected no PVC for an external volume, got %+v", pvc)
+	}
+}
+
+func TestPVCNameFor(t *testing.T) {
+	if got := PVCNameFor("data", kobject.NamedVolumeConfig{}); got != "data" {
+		t.Errorf("PVCNameFor() = %q, want %q", got, "data")
+	}
+	if got := PVCNameFor("data", kobject.NamedVolumeConfig{External: true, ExternalName: "preexisting-pvc"}); got != "preexisting-pvc" {
+		t.Errorf("PVCNameFor() = %q, want %q", got, "preexisting-pvc")
+	}
+}
+
+func TestApplyNamedVolumes(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	service := kobject.ServiceConfig{
+		NamedVolumes: []kobject.ServiceVolumeConfig{
+			{VolumeName: "data", Target: "/var/lib/data", ReadOnly: true},
+		},
+	}
+	namedVolumes := map[string]kobject.NamedVolumeConfig{"data": {}}
+
+	ApplyNamedVolumes(&podSpec, service, namedVolumes)
+
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].PersistentVolumeClaim == nil {
+		t.Fatalf("Volumes = %+v", podSpec.Volumes)
+	}
+	if podSpec.Volumes[0].PersistentVolumeClaim.ClaimName != "data" {
+		t.Errorf("ClaimName = %q, want %q", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName, "data")
+	}
+	if len(podSpec.Containers[0].VolumeMounts) != 1 || podSpec.Containers[0].VolumeMounts[0].MountPath != "/var/lib/data" {
+		t.Fatalf("VolumeMounts = %+v", podSpec.Containers[0].VolumeMounts)
+	}
+}
+
+func TestApplyConfigsAndSecrets(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	mode := uint32(0o400)
+	service := kobject.ServiceConfig{
+		Configs: []kobject.FileReference{{Source: "app-config", Target: "/etc/app/config.yml"}},
+		Secrets: []kobject.FileReference{{Source: "app-secret", Mode: &mode}},
+	}
+	configMaps := map[string]kobject.FileConfig{"app-config": {Content: []byte("k=v")}}
+	secrets := map[string]kobject.FileConfig{
+		"app-secret":      {Content: []byte("s3cr3t")},
+		"external-secret": {External: true},
+	}
+
+	ApplyConfigsAndSecrets(&podSpec, service, configMaps, secrets)
+
+	if len(podSpec.Volumes) != 2 {
+		t.Fatalf("Volumes = %+v, want 2 entries", podSpec.Volumes)
+	}
+	if len(podSpec.Containers[0].VolumeMounts) != 2 {
+		t.Fatalf("VolumeMounts = %+v, want 2 entries", podSpec.Containers[0].VolumeMounts)
+	}
+
+	var sawConfig, sawSecret bool
+	for _, v := range podSpec.Volumes {
+		if v.Name == "app-config" {
+			sawConfig = v.ConfigMap != nil && v.ConfigMap.Name == "app-config"
+		}
+		if v.Name == "app-secret" {
+			sawSecret = v.Secret != nil && v.Secret.SecretName == "app-secret" && v.Secret.DefaultMode != nil && *v.Secret.DefaultMode == int32(mode)
+		}
+	}
+	if !sawConfig {
+		t.Errorf("Volumes = %+v, missing a ConfigMap volume for app-config", podSpec.Volumes)
+	}
+	if !sawSecret {
+		t.Errorf("Volumes = %+v, missing a Secret volume for app-secret with DefaultMode set", podSpec.Volumes)
+	}
+
+	for _, m := range podSpec.Containers[0].VolumeMounts {
+		if m.Name == "app-config" && m.MountPath != "/etc/app/config.yml" {
+			t.Errorf("MountPath = %q, want %q", m.MountPath, "/etc/app/config.yml")
+		}
+		if m.Name == "app-secret" && m.MountPath != "/app-secret" {
+			t.Errorf("MountPath = %q, want default %q", m.MountPath, "/app-secret")
+		}
+	}
+}
+
+func TestApplyConfigsAndSecretsSkipsUnknown(t *testing.T) {
+	podSpec := InitPodSpec("web", kobject.ServiceConfig{Image: "nginx"})
+	service := kobject.ServiceConfig{Configs: []kobject.FileReference{{Source: "missing-config"}}}
+
+	ApplyConfigsAndSecrets(&podSpec, service, map[string]kobject.FileConfig{}, map[string]kobject.FileConfig{})
+
+	if len(podSpec.Volumes) != 0 {
+		t.Errorf("Volumes = %+v, want none for an unknown config", podSpec.Volumes)
+	}
+}
+
+func TestCreateConfigMap(t *testing.T) {
+	cm := CreateConfigMap("app-config", kobject.FileConfig{Content: []byte("key=value")})
+	if cm == nil || cm.Data["app-config"] != "key=value" {
+		t.Fatalf("CreateConfigMap() = %+v", cm)
+	}
+
+	if got := CreateConfigMap("app-config", kobject.FileConfig{External: true}); got != nil {
+		t.Errorf("expected no ConfigMap for an external config, got %+v", got)
+	}
+}
+
+func TestCreateSecret(t *testing.T) {
+	secret := CreateSecret("app-secret", kobject.FileConfig{Content: []byte("s3cr3t")})
+	if secret == nil || string(secret.Data["app-secret"]) != "s3cr3t" {
+		t.Fatalf("CreateSecret() = %+v", secret)
+	}
+
+	if got := CreateSecret("app-secret", kobject.FileConfig{External: true}); got != nil {
+		t.Errorf("expected no Secret for an external secret, got %+v", got)
+	}
+}